@@ -5,8 +5,12 @@ import (
 	"flag"
 	"fmt"
 	"net"
+	"net/rpc"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -19,6 +23,8 @@ import (
 	"github.com/SkycoinProject/skywire-mainnet/pkg/app/appnet"
 	"github.com/SkycoinProject/skywire-mainnet/pkg/routing"
 	"github.com/SkycoinProject/skywire-mainnet/pkg/skyenv"
+
+	"github.com/skycoin/skywire/pkg/servicedisc"
 )
 
 const (
@@ -30,6 +36,14 @@ const (
 const (
 	serverDialInitBO = 1 * time.Second
 	serverDialMaxBO  = 10 * time.Second
+	probeTimeout     = 3 * time.Second
+
+	// defaultServiceDiscAddr is used when -disc-addr is not given.
+	defaultServiceDiscAddr = "https://service-discovery.skywire.skycoin.com"
+
+	// statusAddr is where the RPC gateway exposing the current server/candidates is served, so
+	// that `skywire-cli vpn status` can dial it without going through the visor.
+	statusAddr = "localhost:7050"
 )
 
 var (
@@ -37,7 +51,13 @@ var (
 	r   = netutil.NewRetrier(log, serverDialInitBO, serverDialMaxBO, 0, 1)
 )
 
-var serverPKStr = flag.String("srv", "", "PubKey of the server to connect to")
+var (
+	serverPKStr   = flag.String("srv", "", "PubKey of the server to connect to. If unset, servers are discovered automatically.")
+	country       = flag.String("country", "", "only consider VPN servers advertised in this country")
+	minBandwidth  = flag.Int("min-bandwidth", 0, "only consider VPN servers advertising at least this much bandwidth, in kbps")
+	excludePKsStr = flag.String("exclude-pk", "", "comma-separated list of server pub keys to never select")
+	discAddr      = flag.String("disc-addr", defaultServiceDiscAddr, "address of the service discovery")
+)
 
 func dialServer(appCl *app.Client, pk cipher.PubKey) (net.Conn, error) {
 	var conn net.Conn
@@ -57,19 +77,124 @@ func dialServer(appCl *app.Client, pk cipher.PubKey) (net.Conn, error) {
 	return conn, nil
 }
 
-func main() {
-	flag.Parse()
+// resolveCandidates returns the ordered list of server candidates to try: just serverPK if one
+// was given explicitly, otherwise the result of queryCandidates.
+func resolveCandidates(appCl *app.Client) ([]cipher.PubKey, error) {
+	if *serverPKStr != "" {
+		serverPK := cipher.PubKey{}
+		if err := serverPK.UnmarshalText([]byte(*serverPKStr)); err != nil {
+			return nil, fmt.Errorf("invalid VPN server pub key: %w", err)
+		}
+		return []cipher.PubKey{serverPK}, nil
+	}
+
+	return queryCandidates(appCl)
+}
+
+// queryCandidates asks the service discovery for every VPN server currently advertised that
+// passes the configured filters, ranked by dial latency (servers that don't respond within
+// probeTimeout are tried last, in last-seen order). It is called both for the initial candidate
+// list and to refresh it once the known candidates are exhausted, so that a server advertised
+// after this process started is still reachable without having to restart it.
+func queryCandidates(appCl *app.Client) ([]cipher.PubKey, error) {
+	var excludePKs []cipher.PubKey
+	for _, s := range strings.Split(*excludePKsStr, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		var pk cipher.PubKey
+		if err := pk.UnmarshalText([]byte(s)); err != nil {
+			return nil, fmt.Errorf("invalid -exclude-pk entry %q: %w", s, err)
+		}
+		excludePKs = append(excludePKs, pk)
+	}
 
-	if *serverPKStr == "" {
-		log.Fatalln("VPN server pub key is missing")
+	discC := servicedisc.NewClient(logging.MustGetLogger("vpn_client:servicedisc"), *discAddr)
+
+	services, err := discC.Services(context.Background(), servicedisc.Filter{
+		Type:         servicedisc.ServiceTypeVPN,
+		Country:      *country,
+		MinBandwidth: *minBandwidth,
+		ExcludePKs:   excludePKs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query service discovery: %w", err)
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no VPN servers advertised that satisfy the given filters")
 	}
 
-	serverPK := cipher.PubKey{}
-	if err := serverPK.UnmarshalText([]byte(*serverPKStr)); err != nil {
-		log.WithError(err).Fatalln("Invalid VPN server pub key")
+	return rankByLatency(appCl, services), nil
+}
+
+// rankByLatency probes every candidate concurrently (dial + immediate close) and orders them by
+// how quickly they responded. Candidates that don't respond within probeTimeout are appended
+// last, in servicedisc.RankByLastSeen order.
+func rankByLatency(appCl *app.Client, services []servicedisc.Service) []cipher.PubKey {
+	type probed struct {
+		pk      cipher.PubKey
+		latency time.Duration
+		ok      bool
 	}
 
-	log.Infof("Connecting to VPN server %s", serverPK.String())
+	results := make([]probed, len(services))
+
+	var wg sync.WaitGroup
+	for i, svc := range services {
+		wg.Add(1)
+		go func(i int, svc servicedisc.Service) {
+			defer wg.Done()
+
+			start := time.Now()
+			conn, err := appCl.Dial(appnet.Addr{Net: netType, PubKey: svc.Addr, Port: vpnPort})
+			if err != nil {
+				results[i] = probed{pk: svc.Addr}
+				return
+			}
+			latency := time.Since(start)
+			_ = conn.Close() //nolint:errcheck
+
+			results[i] = probed{pk: svc.Addr, latency: latency, ok: true}
+		}(i, svc)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(probeTimeout):
+	}
+
+	var unranked []servicedisc.Service
+	var responded []probed
+	for i, p := range results {
+		if p.ok {
+			responded = append(responded, p)
+		} else {
+			unranked = append(unranked, services[i])
+		}
+	}
+
+	sort.Slice(responded, func(i, j int) bool { return responded[i].latency < responded[j].latency })
+
+	ranked := make([]cipher.PubKey, 0, len(services))
+	for _, p := range responded {
+		ranked = append(ranked, p.pk)
+	}
+	for _, svc := range servicedisc.RankByLastSeen(unranked) {
+		ranked = append(ranked, svc.Addr)
+	}
+
+	return ranked
+}
+
+func main() {
+	flag.Parse()
 
 	appCfg, err := app.ClientConfigFromEnv()
 	if err != nil {
@@ -84,22 +209,17 @@ func main() {
 		appClient.Close()
 	}()
 
-	appConn, err := dialServer(appClient, serverPK)
+	candidates, err := resolveCandidates(appClient)
 	if err != nil {
-		log.WithError(err).Fatalln("Error connecting to VPN server")
+		log.WithError(err).Fatalln("Error resolving VPN server candidates")
 	}
-	defer func() {
-		if err := appConn.Close(); err != nil {
-			log.WithError(err).Errorln("Error closing connection to the VPN server")
-		}
-	}()
 
-	log.Infof("Dialed %s", appConn.RemoteAddr())
+	// fixedServer is true when the user pinned -srv: there is nothing to re-query in that case,
+	// so exhausting the single candidate is fatal, same as before.
+	fixedServer := *serverPKStr != ""
 
-	vpnClient, err := vpn.NewClient(log, appConn)
-	if err != nil {
-		log.WithError(err).Fatalln("Error creating VPN client")
-	}
+	status := newStatusServer(candidates)
+	go status.serve()
 
 	osSigs := make(chan os.Signal, 2)
 	sigs := []os.Signal{syscall.SIGTERM, syscall.SIGINT}
@@ -107,12 +227,183 @@ func main() {
 		signal.Notify(osSigs, sig)
 	}
 
-	go func() {
-		<-osSigs
-		vpnClient.Close()
-	}()
+	for {
+		serverPK, ok := status.next()
+		if !ok {
+			if fixedServer {
+				log.Fatalln("Exhausted all VPN server candidates")
+			}
+
+			log.Infoln("Exhausted known VPN server candidates, re-querying service discovery")
+			fresh, err := queryCandidates(appClient)
+			if err != nil {
+				log.WithError(err).Fatalln("Error re-querying VPN server candidates after exhaustion")
+			}
+			if status.addCandidates(fresh) == 0 {
+				log.Fatalln("Exhausted all VPN server candidates")
+			}
 
-	if err := vpnClient.Serve(); err != nil {
-		log.WithError(err).Fatalln("Error serving VPN")
+			serverPK, ok = status.next()
+			if !ok {
+				log.Fatalln("Exhausted all VPN server candidates")
+			}
+		}
+
+		log.Infof("Connecting to VPN server %s", serverPK.String())
+
+		appConn, err := dialServer(appClient, serverPK)
+		if err != nil {
+			log.WithError(err).Warnf("Error connecting to VPN server %s, trying next candidate", serverPK)
+			status.markFailed(serverPK)
+			refreshCandidates(appClient, status, fixedServer)
+			continue
+		}
+
+		log.Infof("Dialed %s", appConn.RemoteAddr())
+		status.setCurrent(serverPK)
+
+		vpnClient, err := vpn.NewClient(log, appConn)
+		if err != nil {
+			log.WithError(err).Fatalln("Error creating VPN client")
+		}
+
+		serveErrCh := make(chan error, 1)
+		go func() {
+			serveErrCh <- vpnClient.Serve()
+		}()
+
+		select {
+		case <-osSigs:
+			vpnClient.Close()
+			<-serveErrCh
+			if err := appConn.Close(); err != nil {
+				log.WithError(err).Errorln("Error closing connection to the VPN server")
+			}
+			return
+		case err := <-serveErrCh:
+			// The OS routes and tun device are the responsibility of vpn.Client across Serve
+			// calls; we only retire the dropped server and move on to the next candidate.
+			log.WithError(err).Warnf("VPN tunnel to %s dropped, failing over", serverPK)
+			status.markFailed(serverPK)
+			if err := appConn.Close(); err != nil {
+				log.WithError(err).Errorln("Error closing connection to the VPN server")
+			}
+			refreshCandidates(appClient, status, fixedServer)
+		}
+	}
+}
+
+// refreshCandidates re-queries the service discovery and merges any newly advertised servers
+// into status, so a failover can pick up a server that wasn't known at startup instead of only
+// ever working through the original candidate list. Errors are logged and otherwise ignored: a
+// failed refresh here isn't fatal on its own, since status may still hold untried candidates, and
+// exhaustion is retried (fatally, if it still yields nothing) in the main loop.
+func refreshCandidates(appCl *app.Client, status *statusServer, fixedServer bool) {
+	if fixedServer {
+		return
+	}
+
+	fresh, err := queryCandidates(appCl)
+	if err != nil {
+		log.WithError(err).Warnln("Error re-querying VPN server candidates")
+		return
+	}
+	status.addCandidates(fresh)
+}
+
+// statusServer tracks the current server, every known candidate (tried ones included) and which
+// candidates have failed, and exposes the current server and candidate list over RPC so
+// `skywire-cli vpn status` can show them without talking to the visor.
+type statusServer struct {
+	mx         sync.Mutex
+	candidates []cipher.PubKey
+	tried      map[cipher.PubKey]bool
+	current    cipher.PubKey
+}
+
+func newStatusServer(candidates []cipher.PubKey) *statusServer {
+	return &statusServer{
+		candidates: candidates,
+		tried:      make(map[cipher.PubKey]bool),
+	}
+}
+
+// next returns the next untried candidate, or ok=false if none remain.
+func (s *statusServer) next() (cipher.PubKey, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for _, pk := range s.candidates {
+		if !s.tried[pk] {
+			return pk, true
+		}
 	}
-}
\ No newline at end of file
+	return cipher.PubKey{}, false
+}
+
+// addCandidates merges pks into s.candidates, skipping any already present (whether untried or
+// already tried/failed), and returns how many were genuinely new.
+func (s *statusServer) addCandidates(pks []cipher.PubKey) int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	known := make(map[cipher.PubKey]bool, len(s.candidates))
+	for _, pk := range s.candidates {
+		known[pk] = true
+	}
+
+	added := 0
+	for _, pk := range pks {
+		if known[pk] {
+			continue
+		}
+		known[pk] = true
+		s.candidates = append(s.candidates, pk)
+		added++
+	}
+	return added
+}
+
+func (s *statusServer) markFailed(pk cipher.PubKey) {
+	s.mx.Lock()
+	s.tried[pk] = true
+	s.mx.Unlock()
+}
+
+func (s *statusServer) setCurrent(pk cipher.PubKey) {
+	s.mx.Lock()
+	s.current = pk
+	s.mx.Unlock()
+}
+
+// VPNStatus is the RPC reply type returned by statusServer.Status.
+type VPNStatus struct {
+	Current    cipher.PubKey
+	Candidates []cipher.PubKey
+}
+
+// Status is the RPC method `skywire-cli vpn status` calls.
+func (s *statusServer) Status(_ *struct{}, reply *VPNStatus) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	reply.Current = s.current
+	reply.Candidates = append([]cipher.PubKey{}, s.candidates...)
+
+	return nil
+}
+
+func (s *statusServer) serve() {
+	rpcS := rpc.NewServer()
+	if err := rpcS.RegisterName("VPNGateway", s); err != nil {
+		log.WithError(err).Fatalln("Error registering VPN status RPC gateway")
+	}
+
+	l, err := net.Listen("tcp", statusAddr)
+	if err != nil {
+		log.WithError(err).Errorln("Error listening for VPN status RPC requests")
+		return
+	}
+
+	rpcS.Accept(l)
+}
@@ -5,11 +5,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/skycoin/dmsg/cipher"
-	coinCipher "github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/util/logging"
 	"github.com/spf13/cobra"
 
@@ -28,6 +26,7 @@ var (
 	packageConfig bool
 	hypervisor    bool
 	hypervisorPKs string
+	sourceConf    string
 )
 
 func init() {
@@ -38,6 +37,7 @@ func init() {
 	genConfigCmd.Flags().BoolVarP(&testEnv, "testenv", "t", false, "whether to use production or test deployment service.")
 	genConfigCmd.Flags().BoolVar(&hypervisor, "is-hypervisor", false, "whether to generate config to run this visor as a hypervisor.")
 	genConfigCmd.Flags().StringVar(&hypervisorPKs, "hypervisor-pks", "", "public keys of hypervisors that should be added to this visor")
+	genConfigCmd.Flags().StringVar(&sourceConf, "source-conf", "", "shell-style KEY=VALUE file (or directory of them) to override generated fields from")
 }
 
 var genConfigCmd = &cobra.Command{
@@ -81,15 +81,19 @@ var genConfigCmd = &cobra.Command{
 		}
 
 		if hypervisorPKs != "" {
-			keys := strings.Split(hypervisorPKs, ",")
-			for _, key := range keys {
-				keyParsed, err := coinCipher.PubKeyFromHex(strings.TrimSpace(key))
-				if err != nil {
-					logger.WithError(err).Fatalf("Failed to parse hypervisor private key: %s.", key)
-				}
-				conf.Hypervisors = append(conf.Hypervisors, cipher.PubKey(keyParsed))
+			if err := appendHypervisorPKs(conf, hypervisorPKs); err != nil {
+				logger.WithError(err).Fatal("Failed to parse hypervisor public keys.")
 			}
+		}
 
+		if sourceConf != "" {
+			vars, err := loadSourceConf(sourceConf)
+			if err != nil {
+				logger.WithError(err).Fatalf("Failed to read source conf '%s'.", sourceConf)
+			}
+			if err := applySourceConf(conf, vars); err != nil {
+				logger.WithError(err).Fatalf("Failed to apply source conf '%s'.", sourceConf)
+			}
 		}
 
 		// Save config to file.
@@ -0,0 +1,205 @@
+package visor
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skycoin/dmsg/cipher"
+	coinCipher "github.com/skycoin/skycoin/src/cipher"
+
+	"github.com/skycoin/skywire/pkg/routing"
+	"github.com/skycoin/skywire/pkg/skyenv"
+	"github.com/skycoin/skywire/pkg/visor/visorconfig"
+)
+
+// loadSourceConf reads KEY=VALUE pairs from a shell-style .conf file, or from every regular file
+// in path if it is a directory (files are read in filepath-sorted order, so later files win on
+// conflicting keys). Blank lines and lines starting with '#' are ignored; values may optionally
+// be wrapped in single or double quotes.
+func loadSourceConf(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files = files[:0]
+		for _, e := range entries {
+			if !e.IsDir() {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+		sort.Strings(files)
+	}
+
+	vars := make(map[string]string)
+	for _, f := range files {
+		if err := parseConfFile(f, vars); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", f, err)
+		}
+	}
+
+	return vars, nil
+}
+
+func parseConfFile(path string, vars map[string]string) error {
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		vars[strings.TrimSpace(kv[0])] = strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+	}
+
+	return scanner.Err()
+}
+
+// applySourceConf overrides fields of conf, and the default app arg/autostart/port lists, per the
+// recognised variables present in vars. A variable absent from vars leaves whatever genConf
+// already produced untouched. Of an existing config read via readOldConfig, only the secret key
+// is carried over; its other fields are regenerated by genConf and are not preserved.
+func applySourceConf(conf *visorconfig.V1, vars map[string]string) error {
+	if binPath, ok := vars["BINPATH"]; ok {
+		conf.Launcher.BinPath = binPath
+	}
+
+	if lvl, ok := vars["LOGLVL"]; ok {
+		conf.LogLevel = lvl
+	}
+
+	if pks, ok := vars["HYPERVISORPKS"]; ok {
+		if err := appendHypervisorPKs(conf, pks); err != nil {
+			return err
+		}
+	}
+
+	if val, ok := vars["DMSGHTTP"]; ok {
+		useHTTP, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid DMSGHTTP value %q: %w", val, err)
+		}
+		if useHTTP {
+			conf.Dmsg.Discovery = skyenv.DefaultDmsgHTTPDiscAddr
+		} else {
+			conf.Dmsg.Discovery = skyenv.DefaultDmsgDiscAddr
+		}
+	}
+
+	if srv, ok := vars["PROXYCLIENTPK"]; ok {
+		setAppArg(conf, skyenv.SkysocksClientName, "-srv", srv)
+	}
+
+	if val, ok := vars["STARTPROXYCLIENT"]; ok {
+		autoStart, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid STARTPROXYCLIENT value %q: %w", val, err)
+		}
+		setAppAutoStart(conf, skyenv.SkysocksClientName, autoStart)
+	}
+
+	if addr, ok := vars["PROXYCLIENTADDR"]; ok {
+		setAppArg(conf, skyenv.SkysocksClientName, "-addr", addr)
+	}
+
+	if srv, ok := vars["VPNSERVERPK"]; ok {
+		setAppArg(conf, skyenv.VPNClientName, "-srv", srv)
+	}
+
+	if val, ok := vars["SKYCHATPORT"]; ok {
+		port, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("invalid SKYCHATPORT value %q: %w", val, err)
+		}
+		setAppPort(conf, skyenv.SkychatName, routing.Port(port))
+	}
+
+	return nil
+}
+
+// appendHypervisorPKs parses a comma-separated list of hypervisor public keys and appends them
+// to conf.Hypervisors. It is shared by the --hypervisor-pks flag and the HYPERVISORPKS variable.
+func appendHypervisorPKs(conf *visorconfig.V1, hypervisorPKsCSV string) error {
+	for _, key := range strings.Split(hypervisorPKsCSV, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		keyParsed, err := coinCipher.PubKeyFromHex(key)
+		if err != nil {
+			return fmt.Errorf("failed to parse hypervisor public key %q: %w", key, err)
+		}
+		conf.Hypervisors = append(conf.Hypervisors, cipher.PubKey(keyParsed))
+	}
+
+	return nil
+}
+
+// setAppArg overrides the value following flag in appName's Args, appending the flag/value pair
+// if it isn't already present. It is a no-op if conf has no app by that name.
+func setAppArg(conf *visorconfig.V1, appName, flag, value string) {
+	for i, a := range conf.Launcher.Apps {
+		if a.Name != appName {
+			continue
+		}
+		conf.Launcher.Apps[i].Args = replaceOrAppendArg(a.Args, flag, value)
+		return
+	}
+}
+
+func replaceOrAppendArg(args []string, flag, value string) []string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag {
+			args[i+1] = value
+			return args
+		}
+	}
+	return append(args, flag, value)
+}
+
+// setAppAutoStart overrides whether appName is started automatically. It is a no-op if conf has
+// no app by that name.
+func setAppAutoStart(conf *visorconfig.V1, appName string, autoStart bool) {
+	for i, a := range conf.Launcher.Apps {
+		if a.Name == appName {
+			conf.Launcher.Apps[i].AutoStart = autoStart
+			return
+		}
+	}
+}
+
+// setAppPort overrides the routing port appName listens on. It is a no-op if conf has no app by
+// that name.
+func setAppPort(conf *visorconfig.V1, appName string, port routing.Port) {
+	for i, a := range conf.Launcher.Apps {
+		if a.Name == appName {
+			conf.Launcher.Apps[i].Port = port
+			return
+		}
+	}
+}
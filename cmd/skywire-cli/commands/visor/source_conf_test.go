@@ -0,0 +1,141 @@
+package visor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skywire/pkg/launcher"
+	"github.com/skycoin/skywire/pkg/routing"
+	"github.com/skycoin/skywire/pkg/skyenv"
+	"github.com/skycoin/skywire/pkg/visor/visorconfig"
+)
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadSourceConfFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTempFile(t, dir, "visor.conf", `
+# a comment, and a blank line above
+BINPATH=/usr/bin/apps
+LOGLVL='debug'
+DMSGHTTP="true"
+`)
+
+	vars, err := loadSourceConf(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"BINPATH":  "/usr/bin/apps",
+		"LOGLVL":   "debug",
+		"DMSGHTTP": "true",
+	}, vars)
+}
+
+func TestLoadSourceConfDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTempFile(t, dir, "a.conf", "BINPATH=/a\n")
+	writeTempFile(t, dir, "b.conf", "BINPATH=/b\n")
+
+	// Files are read in filepath-sorted order, so b.conf should win.
+	vars, err := loadSourceConf(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "/b", vars["BINPATH"])
+}
+
+func TestParseConfFileIgnoresMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTempFile(t, dir, "visor.conf", "NOEQUALSHERE\nKEY=value\n")
+
+	vars := make(map[string]string)
+	require.NoError(t, parseConfFile(path, vars))
+	assert.Equal(t, map[string]string{"KEY": "value"}, vars)
+}
+
+func TestLoadSourceConfMissingPath(t *testing.T) {
+	_, err := loadSourceConf(filepath.Join(t.TempDir(), "does-not-exist.conf"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func newTestConf() *visorconfig.V1 {
+	return &visorconfig.V1{
+		Launcher: visorconfig.Launcher{
+			Apps: []launcher.AppConfig{
+				{Name: skyenv.VPNClientName},
+				{Name: skyenv.SkysocksClientName},
+				{Name: skyenv.SkychatName},
+			},
+		},
+	}
+}
+
+func TestApplySourceConfOverrides(t *testing.T) {
+	conf := newTestConf()
+
+	err := applySourceConf(conf, map[string]string{
+		"BINPATH":          "/opt/apps",
+		"LOGLVL":           "debug",
+		"DMSGHTTP":         "true",
+		"PROXYCLIENTPK":    "02deadbeef",
+		"PROXYCLIENTADDR":  "127.0.0.1:1080",
+		"STARTPROXYCLIENT": "true",
+		"VPNSERVERPK":      "03cafebabe",
+		"SKYCHATPORT":      "1234",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/opt/apps", conf.Launcher.BinPath)
+	assert.Equal(t, "debug", conf.LogLevel)
+	assert.Equal(t, skyenv.DefaultDmsgHTTPDiscAddr, conf.Dmsg.Discovery)
+
+	socksArgs := appArgs(conf, skyenv.SkysocksClientName)
+	assert.Equal(t, []string{"-srv", "02deadbeef", "-addr", "127.0.0.1:1080"}, socksArgs)
+	assert.True(t, appAutoStart(conf, skyenv.SkysocksClientName))
+
+	vpnArgs := appArgs(conf, skyenv.VPNClientName)
+	assert.Equal(t, []string{"-srv", "03cafebabe"}, vpnArgs)
+
+	assert.Equal(t, routing.Port(1234), appPort(conf, skyenv.SkychatName))
+}
+
+func TestApplySourceConfInvalidSkychatPort(t *testing.T) {
+	conf := newTestConf()
+	err := applySourceConf(conf, map[string]string{"SKYCHATPORT": "not-a-number"})
+	assert.Error(t, err)
+}
+
+func appArgs(conf *visorconfig.V1, appName string) []string {
+	for _, a := range conf.Launcher.Apps {
+		if a.Name == appName {
+			return a.Args
+		}
+	}
+	return nil
+}
+
+func appAutoStart(conf *visorconfig.V1, appName string) bool {
+	for _, a := range conf.Launcher.Apps {
+		if a.Name == appName {
+			return a.AutoStart
+		}
+	}
+	return false
+}
+
+func appPort(conf *visorconfig.V1, appName string) routing.Port {
+	for _, a := range conf.Launcher.Apps {
+		if a.Name == appName {
+			return a.Port
+		}
+	}
+	return 0
+}
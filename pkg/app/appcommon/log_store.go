@@ -0,0 +1,80 @@
+package appcommon
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skycoin/skycoin/src/util/logging"
+)
+
+// LogStore stores a proc's log entries so they may be retrieved later (e.g. via the hypervisor API).
+type LogStore interface {
+	// LogSince returns the entries logged since the given timestamp (RFC3339Nano), newest last.
+	LogSince(timestamp string) ([]string, error)
+}
+
+// NewProcLogger creates a logger for the given proc, along with the LogStore that backs it.
+func NewProcLogger(conf ProcConfig) (*logging.MasterLogger, LogStore) {
+	store := newMemLogStore()
+
+	mLog := logging.NewMasterLogger()
+	mLog.AddHook(store)
+
+	return mLog, store
+}
+
+// memLogStore is a simple in-memory list of log entries, each with its timestamp. Entries are
+// only ever appended; none are evicted.
+type memLogStore struct {
+	mx      sync.Mutex
+	entries []logEntry
+}
+
+type logEntry struct {
+	ts  time.Time
+	msg string
+}
+
+func newMemLogStore() *memLogStore {
+	return &memLogStore{}
+}
+
+// Levels implements logrus.Hook.
+func (s *memLogStore) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (s *memLogStore) Fire(entry *logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return err
+	}
+
+	s.mx.Lock()
+	s.entries = append(s.entries, logEntry{ts: entry.Time, msg: line})
+	s.mx.Unlock()
+
+	return nil
+}
+
+// LogSince implements LogStore.
+func (s *memLogStore) LogSince(timestamp string) ([]string, error) {
+	since, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	out := make([]string, 0, len(s.entries))
+	for _, e := range s.entries {
+		if e.ts.After(since) {
+			out = append(out, e.msg)
+		}
+	}
+
+	return out, nil
+}
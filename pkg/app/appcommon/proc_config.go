@@ -0,0 +1,87 @@
+package appcommon
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestartPolicy determines whether a proc should be restarted after its command exits.
+type RestartPolicy string
+
+const (
+	// RestartPolicyNever never restarts the proc.
+	RestartPolicyNever RestartPolicy = "never"
+	// RestartPolicyOnFailure restarts the proc only if it exits with an error.
+	RestartPolicyOnFailure RestartPolicy = "on-failure"
+	// RestartPolicyAlways restarts the proc regardless of how it exits.
+	RestartPolicyAlways RestartPolicy = "always"
+)
+
+// Default restart backoff bounds, used when a ProcConfig does not specify its own.
+const (
+	DefaultInitialBackoff = 1 * time.Second
+	DefaultMaxBackoff     = 1 * time.Minute
+)
+
+// ProcConfig defines startup parameters for a proc (an app process instance).
+//
+// ProcKey, rather than AppName, is the unique identity of a running proc: a single app
+// (identified by AppName) may have any number of ProcConfigs - and therefore Procs - running
+// concurrently, each with its own ProcKey, args and environment overrides.
+type ProcConfig struct {
+	AppName     string
+	AppVersion  string
+	ProcKey     ProcKey
+	ProcArgs    []string
+	ProcEnvs    map[string]string
+	ProcWorkDir string
+	// AppSrvAddr is the address of the app server, as seen by the spawned proc. It is computed
+	// automatically from Transport and ProcKey when the proc is (re)started; any value set here
+	// by the caller is overwritten.
+	AppSrvAddr string
+
+	BinaryLoc string
+
+	// Transport selects which ProcTransport the proc connects back to the app server over.
+	// Defaults to TransportAuto.
+	Transport TransportKind
+
+	// RestartPolicy determines whether the proc is restarted once its command exits.
+	// Defaults to RestartPolicyNever.
+	RestartPolicy RestartPolicy
+	// MaxRestarts caps the number of times the proc is restarted. 0 means unlimited.
+	MaxRestarts int
+	// InitialBackoff is the delay before the first restart attempt. Defaults to DefaultInitialBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between restart attempts. Defaults to DefaultMaxBackoff.
+	MaxBackoff time.Duration
+
+	// Capabilities are the Linux CAP_* capabilities (e.g. "CAP_NET_ADMIN") the proc is declared
+	// to need. Starting the proc fails if any of these exceed the capabilities the visor itself
+	// holds. They are applied to the child as ambient capabilities (merged with
+	// AmbientCapabilities), since that is the only mechanism that reliably hands a capability to
+	// a forked child. On non-Linux platforms, declaring any of these (or any of the privilege
+	// fields below) makes NewProc fail.
+	Capabilities []string
+	// AmbientCapabilities are additional Capabilities that matter specifically because the proc
+	// is also switching to RunAsUID/RunAsGID: ambient capabilities are what survive that switch.
+	// Listing a capability here has the same effect as listing it in Capabilities.
+	AmbientCapabilities []string
+	// RunAsUID, if set, runs the proc under the given uid instead of the visor's own.
+	RunAsUID *uint32
+	// RunAsGID, if set, runs the proc under the given gid instead of the visor's own.
+	RunAsGID *uint32
+	// NetNS, if set, is the path of a network namespace (e.g. "/var/run/netns/foo") the proc
+	// should be run inside of.
+	NetNS string
+}
+
+// Envs returns the additional environment variables to be set for the proc, in 'KEY=VALUE' form.
+func (c ProcConfig) Envs() []string {
+	envs := make([]string, 0, len(c.ProcEnvs)+1)
+	for k, v := range c.ProcEnvs {
+		envs = append(envs, fmt.Sprintf("%s=%s", k, v))
+	}
+	envs = append(envs, fmt.Sprintf("APP_SRV_ADDR=%s", c.AppSrvAddr))
+	return envs
+}
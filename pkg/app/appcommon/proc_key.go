@@ -0,0 +1,46 @@
+// Package appcommon defines an app proc's common configuration and types shared between the app server
+// and the app proc itself.
+package appcommon
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// ProcKey is a key that uniquely identifies an app proc instance. Multiple procs of the same app
+// (i.e. sharing the same AppName) are distinguished by their ProcKey.
+type ProcKey [16]byte
+
+// RandProcKey generates a new random ProcKey.
+func RandProcKey() ProcKey {
+	var pk ProcKey
+	if _, err := rand.Read(pk[:]); err != nil {
+		panic(err)
+	}
+	return pk
+}
+
+// String implements fmt.Stringer.
+func (k ProcKey) String() string {
+	return hex.EncodeToString(k[:])
+}
+
+// Null returns true if the ProcKey is unset.
+func (k ProcKey) Null() bool {
+	return k == (ProcKey{})
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k ProcKey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *ProcKey) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	copy(k[:], b)
+	return nil
+}
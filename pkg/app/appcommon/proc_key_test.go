@@ -0,0 +1,25 @@
+package appcommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcKey(t *testing.T) {
+	assert.True(t, (ProcKey{}).Null())
+	assert.False(t, RandProcKey().Null())
+
+	a := RandProcKey()
+	b := RandProcKey()
+	assert.NotEqual(t, a, b, "RandProcKey should not repeat")
+
+	text, err := a.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, a.String(), string(text))
+
+	var roundTripped ProcKey
+	require.NoError(t, roundTripped.UnmarshalText(text))
+	assert.Equal(t, a, roundTripped)
+}
@@ -0,0 +1,39 @@
+package appcommon
+
+import "net"
+
+// TransportKind selects which ProcTransport implementation NewProcTransport constructs.
+type TransportKind string
+
+const (
+	// TransportAuto picks TransportUnix everywhere except Windows, where it picks
+	// TransportNamedPipe. It is the zero value, so an unset ProcConfig.Transport means "auto".
+	TransportAuto TransportKind = ""
+	// TransportUnix uses a Unix-domain socket under os.TempDir, named after the proc's key. On
+	// Windows, NewProcTransport uses a named pipe for it instead.
+	TransportUnix TransportKind = "unix"
+	// TransportTCP uses a TCP listener on the loopback interface. It works on every platform,
+	// and is the only option on platforms with neither Unix sockets nor named pipes.
+	TransportTCP TransportKind = "tcp"
+	// TransportNamedPipe uses a Windows named pipe at \\.\pipe\skywire-<ProcKey>. Only
+	// meaningful on Windows; NewProcTransport falls back to TransportUnix elsewhere.
+	TransportNamedPipe TransportKind = "pipe"
+)
+
+// ProcTransport is how the visor's appserver and a spawned proc set up the net.Conn that
+// Proc.InjectConn expects, in a way that works whether or not the platform has Unix-domain
+// sockets. Exactly one conn is ever expected over a ProcTransport: once accepted, it is not
+// reused across proc restarts - a fresh ProcTransport is created for each (re)start.
+type ProcTransport interface {
+	// Listen starts listening for the proc to dial in. Addr is only meaningful after Listen
+	// returns successfully - implementations that bind an OS-assigned port or path on Listen
+	// have no address to report before then.
+	Listen() (net.Listener, error)
+	// Addr is the value to advertise to the proc (via ProcConfig.AppSrvAddr) so it knows where
+	// to dial back to.
+	Addr() string
+}
+
+// NewProcTransport constructs the ProcTransport that a proc of the given key should use, per
+// kind and the host's runtime.GOOS. It is implemented per-platform, in
+// proc_transport_unix.go and proc_transport_windows.go.
@@ -0,0 +1,28 @@
+package appcommon
+
+import "net"
+
+// tcpProcTransport is a ProcTransport backed by a TCP listener on the loopback interface. It is
+// available on every platform, unlike unixProcTransport and namedPipeProcTransport.
+type tcpProcTransport struct {
+	addr string
+}
+
+func newTCPProcTransport() *tcpProcTransport {
+	return &tcpProcTransport{}
+}
+
+func (t *tcpProcTransport) Listen() (net.Listener, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	t.addr = l.Addr().String()
+
+	return l, nil
+}
+
+func (t *tcpProcTransport) Addr() string {
+	return "tcp://" + t.addr
+}
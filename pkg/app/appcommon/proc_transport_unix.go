@@ -0,0 +1,43 @@
+// +build !windows
+
+package appcommon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// NewProcTransport constructs the ProcTransport a proc of the given key should use. TransportNamedPipe
+// has no meaning off Windows, so it is treated the same as TransportAuto.
+func NewProcTransport(procKey ProcKey, kind TransportKind) ProcTransport {
+	if kind == TransportTCP {
+		return newTCPProcTransport()
+	}
+	return newUnixProcTransport(procKey)
+}
+
+// unixProcTransport is a ProcTransport backed by a Unix-domain socket under os.TempDir, named
+// after the proc's key so restarts of the same proc reuse the same path.
+type unixProcTransport struct {
+	path string
+}
+
+func newUnixProcTransport(procKey ProcKey) *unixProcTransport {
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("skywire-%s.sock", procKey))
+	return &unixProcTransport{path: path}
+}
+
+func (t *unixProcTransport) Listen() (net.Listener, error) {
+	// Remove a stale socket left behind by a proc that crashed without closing it.
+	if err := os.Remove(t.path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("remove stale socket %s: %w", t.path, err)
+	}
+
+	return net.Listen("unix", t.path)
+}
+
+func (t *unixProcTransport) Addr() string {
+	return "unix://" + t.path
+}
@@ -0,0 +1,36 @@
+// +build windows
+
+package appcommon
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// NewProcTransport constructs the ProcTransport a proc of the given key should use.
+func NewProcTransport(procKey ProcKey, kind TransportKind) ProcTransport {
+	if kind == TransportTCP {
+		return newTCPProcTransport()
+	}
+	return newNamedPipeProcTransport(procKey)
+}
+
+// namedPipeProcTransport is a ProcTransport backed by a Windows named pipe, named after the
+// proc's key so restarts of the same proc reuse the same pipe name.
+type namedPipeProcTransport struct {
+	path string
+}
+
+func newNamedPipeProcTransport(procKey ProcKey) *namedPipeProcTransport {
+	return &namedPipeProcTransport{path: fmt.Sprintf(`\\.\pipe\skywire-%s`, procKey)}
+}
+
+func (t *namedPipeProcTransport) Listen() (net.Listener, error) {
+	return winio.ListenPipe(t.path, nil)
+}
+
+func (t *namedPipeProcTransport) Addr() string {
+	return "pipe://" + t.path
+}
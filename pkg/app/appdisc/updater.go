@@ -0,0 +1,37 @@
+// Package appdisc implements the app discovery updater, which keeps a skywire app's service
+// discovery entry up to date while the app's proc is running.
+package appdisc
+
+// ValueType identifies the kind of value being reported via Updater.ChangeValue.
+type ValueType int
+
+// Value types reported by a running proc.
+const (
+	ConnCountValue ValueType = iota
+	ListenerCountValue
+	// RestartsValue reports how many times a proc has been restarted by its RestartPolicy.
+	RestartsValue
+)
+
+// Updater updates a skywire app's entry in the service discovery while the app's proc is running.
+type Updater interface {
+	// Start starts the updater.
+	Start()
+	// Stop stops the updater.
+	Stop()
+	// ChangeValue changes a value in the app's discovery entry.
+	ChangeValue(v ValueType, data []byte) error
+}
+
+// EmptyUpdater is an Updater that does nothing. It is used when an app is not meant to be
+// advertised via the discovery.
+type EmptyUpdater struct{}
+
+// Start implements Updater.
+func (EmptyUpdater) Start() {}
+
+// Stop implements Updater.
+func (EmptyUpdater) Stop() {}
+
+// ChangeValue implements Updater.
+func (EmptyUpdater) ChangeValue(ValueType, []byte) error { return nil }
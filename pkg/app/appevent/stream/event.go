@@ -0,0 +1,41 @@
+// Package stream implements a subscribable event stream for app proc lifecycle and IO events,
+// so that external clients (e.g. a hypervisor, or `skywire-cli`) can observe what a visor's app
+// procs are doing without polling the RPC gateway.
+package stream
+
+import (
+	"time"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+)
+
+// EventKind identifies the kind of ProcEvent.
+type EventKind string
+
+// Supported event kinds.
+const (
+	EventStarted        EventKind = "started"
+	EventExited         EventKind = "exited"
+	EventLog            EventKind = "log"
+	EventConnCountDelta EventKind = "conn_count_delta"
+	EventListenerDelta  EventKind = "listener_count_delta"
+	EventRPCCall        EventKind = "rpc_call"
+)
+
+// ProcEvent is a single event emitted by a proc over its lifetime.
+type ProcEvent struct {
+	Kind    EventKind
+	ProcKey appcommon.ProcKey
+	AppName string
+	Time    time.Time
+
+	// WaitErr is set for EventExited, and holds the string form of the error the proc's cmd
+	// exited with (empty on a clean exit).
+	WaitErr string
+	// Line is set for EventLog, and holds a single line written to the proc's stdout/stderr.
+	Line string
+	// Count is set for EventConnCountDelta/EventListenerDelta.
+	Count int
+	// Method is set for EventRPCCall, and holds the RPC method name that was called.
+	Method string
+}
@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"regexp"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+)
+
+// Filter narrows down the set of ProcEvents a Subscription receives. A zero-value field means
+// "no filtering on this dimension".
+//
+// LogPattern is a regexp source string rather than a compiled *regexp.Regexp so that a Filter
+// can be sent over the wire (e.g. gob-encoded via ServeConn) without issue.
+type Filter struct {
+	ProcKey    *appcommon.ProcKey
+	Kinds      []EventKind
+	LogPattern string
+}
+
+// compiled resolves the filter into a matcher, compiling LogPattern (if any) once up front.
+func (f Filter) compiled() (filterMatcher, error) {
+	m := filterMatcher{Filter: f}
+
+	if f.LogPattern != "" {
+		re, err := regexp.Compile(f.LogPattern)
+		if err != nil {
+			return filterMatcher{}, err
+		}
+		m.logLine = re
+	}
+
+	return m, nil
+}
+
+type filterMatcher struct {
+	Filter
+	logLine *regexp.Regexp
+}
+
+// match reports whether ev passes the filter.
+func (m filterMatcher) match(ev ProcEvent) bool {
+	if m.ProcKey != nil && *m.ProcKey != ev.ProcKey {
+		return false
+	}
+
+	if len(m.Kinds) > 0 {
+		matched := false
+		for _, k := range m.Kinds {
+			if k == ev.Kind {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if m.logLine != nil && ev.Kind == EventLog && !m.logLine.MatchString(ev.Line) {
+		return false
+	}
+
+	return true
+}
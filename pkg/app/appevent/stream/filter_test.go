@@ -0,0 +1,48 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+)
+
+func TestFilterMatch(t *testing.T) {
+	keyA := appcommon.RandProcKey()
+	keyB := appcommon.RandProcKey()
+
+	t.Run("no filter matches everything", func(t *testing.T) {
+		m, err := Filter{}.compiled()
+		require.NoError(t, err)
+		assert.True(t, m.match(ProcEvent{Kind: EventStarted, ProcKey: keyA}))
+	})
+
+	t.Run("proc key filter", func(t *testing.T) {
+		m, err := Filter{ProcKey: &keyA}.compiled()
+		require.NoError(t, err)
+		assert.True(t, m.match(ProcEvent{Kind: EventStarted, ProcKey: keyA}))
+		assert.False(t, m.match(ProcEvent{Kind: EventStarted, ProcKey: keyB}))
+	})
+
+	t.Run("kind filter", func(t *testing.T) {
+		m, err := Filter{Kinds: []EventKind{EventExited, EventLog}}.compiled()
+		require.NoError(t, err)
+		assert.True(t, m.match(ProcEvent{Kind: EventExited}))
+		assert.False(t, m.match(ProcEvent{Kind: EventStarted}))
+	})
+
+	t.Run("log pattern only applies to log events", func(t *testing.T) {
+		m, err := Filter{LogPattern: "^error"}.compiled()
+		require.NoError(t, err)
+		assert.True(t, m.match(ProcEvent{Kind: EventLog, Line: "error: boom"}))
+		assert.False(t, m.match(ProcEvent{Kind: EventLog, Line: "all good"}))
+		assert.True(t, m.match(ProcEvent{Kind: EventStarted}))
+	})
+
+	t.Run("invalid pattern fails to compile", func(t *testing.T) {
+		_, err := Filter{LogPattern: "("}.compiled()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,39 @@
+package stream
+
+import (
+	"encoding/gob"
+	"net"
+)
+
+// ServeConn serves a single subscriber connection: it reads one Filter from conn, then streams
+// every matching ProcEvent to conn (gob-encoded) until writing an event to conn fails (e.g.
+// because the subscriber closed it).
+//
+// This is intentionally transport-agnostic over conn - a dmsg stream and a loopback TCP
+// connection both implement net.Conn - so the visor can expose it over whichever transport its
+// callers can reach it on.
+func (s *Server) ServeConn(conn net.Conn) error {
+	defer conn.Close() //nolint:errcheck
+
+	dec := gob.NewDecoder(conn)
+
+	var f Filter
+	if err := dec.Decode(&f); err != nil {
+		return err
+	}
+
+	sub, err := s.Subscribe(f)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	enc := gob.NewEncoder(conn)
+	for ev := range sub.Events() {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
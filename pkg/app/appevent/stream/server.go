@@ -0,0 +1,123 @@
+package stream
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/skycoin/skycoin/src/util/logging"
+)
+
+// subBufSize is how many events a Subscription buffers before it starts dropping the oldest
+// ones to keep the publisher from blocking on a slow subscriber.
+const subBufSize = 64
+
+// Server fans out published ProcEvents to any number of Subscriptions, each with its own Filter.
+// It is the in-process core of the event-stream subsystem; a transport (e.g. a dmsg/TCP listener
+// serving a thin RPC wrapper around Subscribe) is expected to sit in front of it.
+type Server struct {
+	log *logging.Logger
+
+	mx   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewServer constructs a new Server.
+func NewServer(log *logging.Logger) *Server {
+	if log == nil {
+		log = logging.MustGetLogger("appevent_stream")
+	}
+
+	return &Server{
+		log:  log,
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a new Subscription that receives events matching f until it is closed.
+// It returns an error only if f.LogPattern fails to compile as a regexp.
+func (s *Server) Subscribe(f Filter) (*Subscription, error) {
+	matcher, err := f.compiled()
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Subscription{
+		filter: matcher,
+		events: make(chan ProcEvent, subBufSize),
+		server: s,
+	}
+
+	s.mx.Lock()
+	s.subs[sub] = struct{}{}
+	s.mx.Unlock()
+
+	return sub, nil
+}
+
+// Publish fans ev out to every Subscription whose Filter matches it. A Subscription that is not
+// keeping up has its oldest buffered event dropped (counted by Subscription.Dropped) to make room.
+//
+// ev.Time is stamped here with the current time if the caller left it zero, so every publish
+// site (publishStarted, publishExited, ...) gets a real timestamp for free rather than each
+// having to remember to set one.
+func (s *Server) Publish(ev ProcEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for sub := range s.subs {
+		if !sub.filter.match(ev) {
+			continue
+		}
+
+		select {
+		case sub.events <- ev:
+		default:
+			select {
+			case <-sub.events:
+				atomic.AddUint64(&sub.dropped, 1)
+			default:
+			}
+			select {
+			case sub.events <- ev:
+			default:
+				// subscriber's buffer churned faster than we could make room; drop ev itself.
+				atomic.AddUint64(&sub.dropped, 1)
+			}
+		}
+	}
+}
+
+func (s *Server) unsubscribe(sub *Subscription) {
+	s.mx.Lock()
+	delete(s.subs, sub)
+	s.mx.Unlock()
+	close(sub.events)
+}
+
+// Subscription is a single subscriber's view of the event stream.
+type Subscription struct {
+	filter  filterMatcher
+	events  chan ProcEvent
+	server  *Server
+	dropped uint64
+}
+
+// Events returns the channel over which matching ProcEvents are delivered.
+func (sub *Subscription) Events() <-chan ProcEvent {
+	return sub.events
+}
+
+// Dropped returns how many events were dropped for this Subscription due to back-pressure.
+func (sub *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&sub.dropped)
+}
+
+// Close unregisters the Subscription. Events() is closed once Close returns.
+func (sub *Subscription) Close() {
+	sub.server.unsubscribe(sub)
+}
@@ -0,0 +1,39 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishStampsTime(t *testing.T) {
+	s := NewServer(nil)
+
+	sub, err := s.Subscribe(Filter{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	before := time.Now()
+	s.Publish(ProcEvent{Kind: EventStarted})
+	after := time.Now()
+
+	ev := <-sub.Events()
+	assert.False(t, ev.Time.Before(before))
+	assert.False(t, ev.Time.After(after))
+}
+
+func TestPublishKeepsCallerSuppliedTime(t *testing.T) {
+	s := NewServer(nil)
+
+	sub, err := s.Subscribe(Filter{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	want := time.Now().Add(-time.Hour)
+	s.Publish(ProcEvent{Kind: EventStarted, Time: want})
+
+	ev := <-sub.Events()
+	assert.Equal(t, want, ev.Time)
+}
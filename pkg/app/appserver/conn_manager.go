@@ -0,0 +1,53 @@
+package appserver
+
+import (
+	"net"
+	"sync"
+)
+
+// connManager tracks the connections a proc has open over the RPC gateway and notifies
+// deltaInformers whenever the open connection count changes.
+type connManager struct {
+	mx        sync.Mutex
+	conns     map[uint16]net.Conn
+	informers []*deltaInformer
+}
+
+func newConnManager() *connManager {
+	return &connManager{conns: make(map[uint16]net.Conn)}
+}
+
+// AddDeltaInformer registers a new deltaInformer that receives the open connection count
+// whenever it changes.
+func (cm *connManager) AddDeltaInformer() *deltaInformer {
+	cm.mx.Lock()
+	defer cm.mx.Unlock()
+
+	di := newDeltaInformer()
+	cm.informers = append(cm.informers, di)
+	di.push(len(cm.conns))
+
+	return di
+}
+
+func (cm *connManager) notify() {
+	for _, di := range cm.informers {
+		di.push(len(cm.conns))
+	}
+}
+
+// CloseAll closes all tracked connections and the registered deltaInformers.
+func (cm *connManager) CloseAll() {
+	cm.mx.Lock()
+	defer cm.mx.Unlock()
+
+	for id, conn := range cm.conns {
+		_ = conn.Close() //nolint:errcheck
+		delete(cm.conns, id)
+	}
+
+	for _, di := range cm.informers {
+		di.close()
+	}
+	cm.informers = nil
+}
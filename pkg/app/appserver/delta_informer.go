@@ -0,0 +1,29 @@
+package appserver
+
+// deltaInformer emits the current count of open connections/listeners whenever it changes.
+type deltaInformer struct {
+	ch chan int
+}
+
+func newDeltaInformer() *deltaInformer {
+	return &deltaInformer{ch: make(chan int, 16)}
+}
+
+// Chan returns the channel over which count deltas are pushed.
+func (i *deltaInformer) Chan() <-chan int {
+	return i.ch
+}
+
+func (i *deltaInformer) push(n int) {
+	select {
+	case i.ch <- n:
+	default:
+		// drop if the consumer is not keeping up; the next delta will carry the up-to-date count.
+		<-i.ch
+		i.ch <- n
+	}
+}
+
+func (i *deltaInformer) close() {
+	close(i.ch)
+}
@@ -0,0 +1,53 @@
+package appserver
+
+import (
+	"net"
+	"sync"
+)
+
+// listenerManager tracks the listeners a proc has open over the RPC gateway and notifies
+// deltaInformers whenever the open listener count changes.
+type listenerManager struct {
+	mx        sync.Mutex
+	listeners map[uint16]net.Listener
+	informers []*deltaInformer
+}
+
+func newListenerManager() *listenerManager {
+	return &listenerManager{listeners: make(map[uint16]net.Listener)}
+}
+
+// AddDeltaInformer registers a new deltaInformer that receives the open listener count
+// whenever it changes.
+func (lm *listenerManager) AddDeltaInformer() *deltaInformer {
+	lm.mx.Lock()
+	defer lm.mx.Unlock()
+
+	di := newDeltaInformer()
+	lm.informers = append(lm.informers, di)
+	di.push(len(lm.listeners))
+
+	return di
+}
+
+func (lm *listenerManager) notify() {
+	for _, di := range lm.informers {
+		di.push(len(lm.listeners))
+	}
+}
+
+// CloseAll closes all tracked listeners and the registered deltaInformers.
+func (lm *listenerManager) CloseAll() {
+	lm.mx.Lock()
+	defer lm.mx.Unlock()
+
+	for id, l := range lm.listeners {
+		_ = l.Close() //nolint:errcheck
+		delete(lm.listeners, id)
+	}
+
+	for _, di := range lm.informers {
+		di.close()
+	}
+	lm.informers = nil
+}
@@ -1,8 +1,10 @@
 package appserver
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/rpc"
 	"os"
@@ -12,29 +14,43 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/skycoin/dmsg/netutil"
 	"github.com/skycoin/skycoin/src/util/logging"
 
 	"github.com/skycoin/skywire/pkg/app/appcommon"
 	"github.com/skycoin/skywire/pkg/app/appdisc"
+	"github.com/skycoin/skywire/pkg/app/appevent/stream"
 )
 
 var (
 	errProcAlreadyRunning = errors.New("process already running")
 	errProcNotStarted     = errors.New("process is not started")
+
+	// errProcRestart is returned by the supervision loop's retrier callback to signal that the
+	// proc should be respawned, as opposed to a nil/terminal error which stops supervision.
+	errProcRestart = errors.New("proc exited, restarting per restart policy")
 )
 
+// restarterFactor is the multiplier applied to the backoff between consecutive restarts.
+const restarterFactor = 2
+
 // Proc is an instance of a skywire app. It encapsulates the running process itself and the RPC server for app/visor
-// communication.
-// TODO(evanlinjin): In the future, we will implement the ability to run multiple instances (procs) of a single app.
+// communication. Multiple Procs may share the same AppName - conf.ProcKey is what uniquely identifies a Proc.
 type Proc struct {
-	disc appdisc.Updater // app discovery client
-	conf appcommon.ProcConfig
-	log  *logging.Logger
-
-	logDB appcommon.LogStore
-
-	cmd       *exec.Cmd
+	disc   appdisc.Updater // app discovery client
+	conf   appcommon.ProcConfig
+	log    *logging.Logger
+	events *stream.Server // may be nil; publishes proc lifecycle/IO events for subscribers
+
+	logDB      appcommon.LogStore
+	appLog     *logging.MasterLogger
+	moduleName string
+
+	cmdMx     sync.Mutex    // guards cmd/listener: reset() (supervise goroutine) writes, Stop() reads
+	cmd       *exec.Cmd     // replaced with a fresh instance on every (re)start - see cmdMx
+	listener  net.Listener  // the ProcTransport's listener for the current (re)start - see cmdMx
 	isRunning int32
+	restarts  int32
 	waitMx    sync.Mutex
 	waitErr   error
 
@@ -42,31 +58,110 @@ type Proc struct {
 	conn     net.Conn           // connection to proc - introduced AFTER proc is started
 	connCh   chan struct{}      // push here when conn is received - protected by 'connOnce'
 	connOnce sync.Once          // ensures we only push to 'connCh' once
+
+	cancel context.CancelFunc // cancels an in-flight restart backoff wait
 }
 
-// NewProc constructs `Proc`.
-func NewProc(mLog *logging.MasterLogger, conf appcommon.ProcConfig, disc appdisc.Updater) *Proc {
+// setCmd atomically replaces cmd/listener. Called by NewProc and reset() - the latter runs on
+// the supervise goroutine, concurrently with Stop()/Cmd() reading them from the caller's
+// goroutine, hence the lock.
+func (p *Proc) setCmd(cmd *exec.Cmd, listener net.Listener) {
+	p.cmdMx.Lock()
+	p.cmd = cmd
+	p.listener = listener
+	p.cmdMx.Unlock()
+}
+
+// getCmd returns the current cmd/listener. See setCmd.
+func (p *Proc) getCmd() (*exec.Cmd, net.Listener) {
+	p.cmdMx.Lock()
+	defer p.cmdMx.Unlock()
+	return p.cmd, p.listener
+}
+
+// NewProc constructs `Proc`. events may be nil, in which case the proc's lifecycle and IO are
+// not published anywhere (only the RPC gateway conn/listener count informers still run).
+//
+// NewProc can fail: conf's declared privileges may be invalid or exceed what the visor itself
+// holds, or setting up the proc transport may fail for OS-level reasons (unwritable temp dir,
+// named-pipe creation failure, ...). Since conf may come from an untrusted/attacker-influenced
+// source, callers must handle this error rather than crash the whole visor process.
+func NewProc(mLog *logging.MasterLogger, conf appcommon.ProcConfig, disc appdisc.Updater, events *stream.Server) (*Proc, error) {
 	if mLog == nil {
 		mLog = logging.NewMasterLogger()
 	}
 	moduleName := fmt.Sprintf("proc:%s:%s", conf.AppName, conf.ProcKey)
 
-	cmd := exec.Command(conf.BinaryLoc, conf.ProcArgs...) // nolint:gosec
-	cmd.Dir = conf.ProcWorkDir
-	cmd.Env = append(os.Environ(), conf.Envs()...)
-
 	appLog, appLogDB := appcommon.NewProcLogger(conf)
-	cmd.Stdout = appLog.WithField("_module", moduleName).WithField("func", "(STDOUT)").Writer()
-	cmd.Stderr = appLog.WithField("_module", moduleName).WithField("func", "(STDERR)").Writer()
 
-	return &Proc{
-		disc:   disc,
-		conf:   conf,
-		log:    mLog.PackageLogger(moduleName),
-		logDB:  appLogDB,
-		cmd:    cmd,
-		connCh: make(chan struct{}, 1),
+	p := &Proc{
+		disc:       disc,
+		conf:       conf,
+		log:        mLog.PackageLogger(moduleName),
+		events:     events,
+		logDB:      appLogDB,
+		appLog:     appLog,
+		moduleName: moduleName,
+	}
+
+	cmd, listener, err := p.newCmd()
+	if err != nil {
+		return nil, err
+	}
+	p.setCmd(cmd, listener)
+	p.connCh = make(chan struct{}, 1)
+
+	return p, nil
+}
+
+// newCmd builds a fresh *exec.Cmd from conf. A new one is needed every time the proc is
+// (re)started, as an exec.Cmd cannot be reused once it has run once.
+//
+// It also (re)establishes the ProcTransport the proc dials back to the app server over: conf
+// carries no usable AppSrvAddr of its own (each ProcKey gets a fresh transport address/path), so
+// one is created and listened on here, and conf.AppSrvAddr is set from it before building cmd.Env.
+func (p *Proc) newCmd() (*exec.Cmd, net.Listener, error) {
+	transport := appcommon.NewProcTransport(p.conf.ProcKey, p.conf.Transport)
+
+	listener, err := transport.Listen()
+	if err != nil {
+		return nil, nil, fmt.Errorf("listen on proc transport: %w", err)
+	}
+	p.conf.AppSrvAddr = transport.Addr()
+
+	cmd := exec.Command(p.conf.BinaryLoc, p.conf.ProcArgs...) // nolint:gosec
+	cmd.Dir = p.conf.ProcWorkDir
+	cmd.Env = append(os.Environ(), p.conf.Envs()...)
+
+	stdoutLog := p.appLog.WithField("_module", p.moduleName).WithField("func", "(STDOUT)").Writer()
+	stderrLog := p.appLog.WithField("_module", p.moduleName).WithField("func", "(STDERR)").Writer()
+
+	cmd.Stdout = io.MultiWriter(stdoutLog, newLogEventWriter(p))
+	cmd.Stderr = io.MultiWriter(stderrLog, newLogEventWriter(p))
+
+	if err := applyProcPrivileges(cmd, p.conf); err != nil {
+		_ = listener.Close() //nolint:errcheck
+		return nil, nil, err
+	}
+
+	go p.acceptConn(listener)
+
+	return cmd, listener, nil
+}
+
+// acceptConn accepts the single conn the proc dials in on l, and hands it to InjectConn. l is
+// closed once that conn is accepted (or accepting it fails), since a ProcTransport is never
+// reused beyond its one expected conn.
+func (p *Proc) acceptConn(l net.Listener) {
+	defer func() { _ = l.Close() }() //nolint:errcheck
+
+	conn, err := l.Accept()
+	if err != nil {
+		p.log.WithError(err).Debug("Proc transport listener closed before proc connected.")
+		return
 	}
+
+	p.InjectConn(conn)
 }
 
 // Logs obtains the log store.
@@ -76,7 +171,8 @@ func (p *Proc) Logs() appcommon.LogStore {
 
 // Cmd returns the internal cmd name.
 func (p *Proc) Cmd() *exec.Cmd {
-	return p.cmd
+	cmd, _ := p.getCmd()
+	return cmd
 }
 
 // InjectConn introduces the connection to the Proc after it is started.
@@ -88,7 +184,7 @@ func (p *Proc) InjectConn(conn net.Conn) bool {
 	p.connOnce.Do(func() {
 		ok = true
 		p.conn = conn
-		p.rpcGW = NewRPCGateway(p.log)
+		p.rpcGW = NewRPCGateway(p.log, p.events, p.conf.ProcKey, p.conf.AppName)
 
 		// Send ready signal.
 		p.connCh <- struct{}{}
@@ -115,6 +211,7 @@ func (p *Proc) awaitConn() bool {
 				p.log.WithError(err).WithField("value", appdisc.ConnCountValue).
 					Error("Failed to change app discovery value.")
 			}
+			p.publishCountDelta(stream.EventConnCountDelta, n)
 		}
 	}()
 
@@ -125,6 +222,7 @@ func (p *Proc) awaitConn() bool {
 				p.log.WithError(err).WithField("value", appdisc.ListenerCountValue).
 					Error("Failed to change app discovery value.")
 			}
+			p.publishCountDelta(stream.EventListenerDelta, n)
 		}
 	}()
 
@@ -134,62 +232,166 @@ func (p *Proc) awaitConn() bool {
 	return true
 }
 
+// reset prepares the Proc to be (re)started: a fresh cmd, and a fresh connCh/connOnce pair so
+// that InjectConn can be used again for the new process instance.
+func (p *Proc) reset() error {
+	cmd, listener, err := p.newCmd()
+	if err != nil {
+		return err
+	}
+
+	p.setCmd(cmd, listener)
+	p.connCh = make(chan struct{}, 1)
+	p.connOnce = sync.Once{}
+
+	return nil
+}
+
+// awaitAndWait waits for the proc conn and for the already-started cmd to exit, and tears down
+// the conn/RPC gateway state. It returns the error the cmd exited with, if any.
+func (p *Proc) awaitAndWait() error {
+	cmd, _ := p.getCmd()
+
+	p.log.Infoln("AWAITING CONN")
+	if ok := p.awaitConn(); !ok {
+		_ = cmd.Process.Kill() //nolint:errcheck
+		return errProcNotStarted
+	}
+	p.log.Infoln("AWAITED CONN")
+
+	// App discovery start/stop.
+	p.disc.Start()
+	defer p.disc.Stop()
+	p.log.Infoln("WAITING CMD")
+
+	// Wait for proc to exit.
+	p.waitErr = cmd.Wait()
+	p.log.Errorf("CMD EXITED WITH %v", p.waitErr)
+	p.publishExited(p.waitErr)
+
+	// Close proc conn and associated listeners and connections.
+	if err := p.conn.Close(); err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
+		p.log.WithError(err).Warn("Closing proc conn returned unexpected error.")
+	}
+	p.rpcGW.cm.CloseAll()
+	p.rpcGW.lm.CloseAll()
+
+	return p.waitErr
+}
+
+// shouldRestart reports whether, per the configured RestartPolicy, the proc should be
+// respawned after exiting with runErr.
+func (p *Proc) shouldRestart(runErr error) bool {
+	switch p.conf.RestartPolicy {
+	case appcommon.RestartPolicyAlways:
+		return true
+	case appcommon.RestartPolicyOnFailure:
+		return runErr != nil
+	default:
+		return false
+	}
+}
+
+// RestartCount returns how many times the proc has been restarted so far.
+func (p *Proc) RestartCount() int {
+	return int(atomic.LoadInt32(&p.restarts))
+}
+
+// supervise drives runOnce in a loop, respecting the configured RestartPolicy, and backs off
+// exponentially (via netutil.Retrier) between restarts. It releases waitMx once supervision ends
+// for good, i.e. once the proc is not going to be restarted anymore, or ctx is cancelled.
+func (p *Proc) supervise(ctx context.Context) {
+	defer p.waitMx.Unlock()
+
+	initialBO := p.conf.InitialBackoff
+	if initialBO <= 0 {
+		initialBO = appcommon.DefaultInitialBackoff
+	}
+	maxBO := p.conf.MaxBackoff
+	if maxBO <= 0 {
+		maxBO = appcommon.DefaultMaxBackoff
+	}
+
+	retrier := netutil.NewRetrier(p.log, initialBO, maxBO, p.conf.MaxRestarts, restarterFactor)
+
+	first := true
+
+	err := retrier.Do(ctx, func() error {
+		if !first {
+			if err := p.reset(); err != nil {
+				return err
+			}
+			atomic.AddInt32(&p.restarts, 1)
+			if err := p.disc.ChangeValue(appdisc.RestartsValue, []byte(strconv.Itoa(p.RestartCount()))); err != nil {
+				p.log.WithError(err).WithField("value", appdisc.RestartsValue).
+					Error("Failed to change app discovery value.")
+			}
+
+			cmd, _ := p.getCmd()
+			if err := cmd.Start(); err != nil {
+				return err
+			}
+			p.log.Infoln("STARTED PROCESS")
+			p.publishStarted()
+		}
+		first = false
+
+		runErr := p.awaitAndWait()
+		if p.shouldRestart(runErr) {
+			return errProcRestart
+		}
+		return nil
+	})
+	if err != nil && err != errProcRestart {
+		p.log.WithError(err).Warn("Proc supervision loop ended.")
+	}
+}
+
 // Start starts the application.
 func (p *Proc) Start() error {
 	if !atomic.CompareAndSwapInt32(&p.isRunning, 0, 1) {
 		return errProcAlreadyRunning
 	}
 
-	// Acquire lock immediately.
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	// Acquire lock immediately; released once supervision ends for good.
 	p.waitMx.Lock()
 
-	if err := p.cmd.Start(); err != nil {
+	cmd, _ := p.getCmd()
+	if err := cmd.Start(); err != nil {
 		p.waitMx.Unlock()
+		cancel()
 		return err
 	}
-
 	p.log.Infoln("STARTED PROCESS")
+	p.publishStarted()
 
-	go func() {
-		p.log.Infoln("AWAITING CONN")
-		if ok := p.awaitConn(); !ok {
-			_ = p.cmd.Process.Kill() //nolint:errcheck
-			p.waitMx.Unlock()
-			return
-		}
-		p.log.Infoln("AWAITED CONN")
-
-		// App discovery start/stop.
-		p.disc.Start()
-		defer p.disc.Stop()
-		p.log.Infoln("WAITING CMD")
-
-		// Wait for proc to exit.
-		p.waitErr = p.cmd.Wait()
-		p.log.Errorf("CMD EXITED WITH %v", p.waitErr)
-
-		// Close proc conn and associated listeners and connections.
-		if err := p.conn.Close(); err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
-			p.log.WithError(err).Warn("Closing proc conn returned unexpected error.")
-		}
-		p.rpcGW.cm.CloseAll()
-		p.rpcGW.lm.CloseAll()
-
-		// Unlock.
-		p.waitMx.Unlock()
-	}()
+	go p.supervise(ctx)
 
 	return nil
 }
 
-// Stop stops the application.
+// Stop stops the application, cancelling any in-flight restart backoff wait.
 func (p *Proc) Stop() error {
 	if atomic.LoadInt32(&p.isRunning) == 0 {
 		return errProcNotStarted
 	}
 
-	if p.cmd.Process != nil {
-		err := p.cmd.Process.Signal(os.Interrupt)
+	if p.cancel != nil {
+		p.cancel()
+	}
+
+	cmd, listener := p.getCmd()
+
+	// Unblock acceptConn if the proc never dialed back in.
+	if listener != nil {
+		_ = listener.Close() //nolint:errcheck
+	}
+
+	if cmd.Process != nil {
+		err := cmd.Process.Signal(os.Interrupt)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,94 @@
+package appserver
+
+import (
+	"bytes"
+
+	"github.com/skycoin/skywire/pkg/app/appevent/stream"
+)
+
+// logEventWriter splits the bytes written to it into lines and publishes each complete line as
+// an EventLog ProcEvent. It is meant to sit alongside (not instead of) the writer that feeds the
+// existing logDB, via io.MultiWriter - so wiring it up does not change logDB behaviour.
+type logEventWriter struct {
+	p   *Proc
+	buf bytes.Buffer
+}
+
+func newLogEventWriter(p *Proc) *logEventWriter {
+	return &logEventWriter{p: p}
+}
+
+// Write implements io.Writer.
+func (w *logEventWriter) Write(b []byte) (int, error) {
+	if w.p.events == nil {
+		return len(b), nil
+	}
+
+	w.buf.Write(b)
+
+	for {
+		data := w.buf.Bytes()
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			break
+		}
+		w.p.publishLog(string(data[:idx]))
+		w.buf.Next(idx + 1)
+	}
+
+	return len(b), nil
+}
+
+// publishStarted publishes an EventStarted ProcEvent.
+func (p *Proc) publishStarted() {
+	if p.events == nil {
+		return
+	}
+	p.events.Publish(stream.ProcEvent{
+		Kind:    stream.EventStarted,
+		ProcKey: p.conf.ProcKey,
+		AppName: p.conf.AppName,
+	})
+}
+
+// publishExited publishes an EventExited ProcEvent.
+func (p *Proc) publishExited(waitErr error) {
+	if p.events == nil {
+		return
+	}
+
+	var waitErrStr string
+	if waitErr != nil {
+		waitErrStr = waitErr.Error()
+	}
+
+	p.events.Publish(stream.ProcEvent{
+		Kind:    stream.EventExited,
+		ProcKey: p.conf.ProcKey,
+		AppName: p.conf.AppName,
+		WaitErr: waitErrStr,
+	})
+}
+
+// publishLog publishes an EventLog ProcEvent for a single log line.
+func (p *Proc) publishLog(line string) {
+	p.events.Publish(stream.ProcEvent{
+		Kind:    stream.EventLog,
+		ProcKey: p.conf.ProcKey,
+		AppName: p.conf.AppName,
+		Line:    line,
+	})
+}
+
+// publishCountDelta publishes an EventConnCountDelta/EventListenerDelta ProcEvent.
+func (p *Proc) publishCountDelta(kind stream.EventKind, n int) {
+	if p.events == nil {
+		return
+	}
+	p.events.Publish(stream.ProcEvent{
+		Kind:    kind,
+		ProcKey: p.conf.ProcKey,
+		AppName: p.conf.AppName,
+		Count:   n,
+	})
+}
@@ -0,0 +1,178 @@
+package appserver
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/skycoin/skycoin/src/util/logging"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+	"github.com/skycoin/skywire/pkg/app/appdisc"
+	"github.com/skycoin/skywire/pkg/app/appevent/stream"
+)
+
+// ProcManager runs and manages skywire apps. Unlike a single Proc, which is one running instance,
+// ProcManager tracks every Proc keyed by its ProcKey - multiple Procs sharing the same AppName can
+// be started and stopped independently of one another.
+type ProcManager interface {
+	// Start starts a new proc and returns its ProcKey.
+	Start(conf appcommon.ProcConfig, disc appdisc.Updater) (appcommon.ProcKey, error)
+	// Stop stops the proc with the given key.
+	Stop(key appcommon.ProcKey) error
+	// Proc returns the proc with the given key.
+	Proc(key appcommon.ProcKey) (*Proc, bool)
+	// RestartCount returns how many times the proc of the given key has been restarted so far.
+	RestartCount(key appcommon.ProcKey) (int, bool)
+	// ProcByAppName returns the keys of all running procs for the given app name.
+	ProcByAppName(appName string) []appcommon.ProcKey
+	// List returns the keys of all running procs.
+	List() []appcommon.ProcKey
+	// StopAll stops all running procs.
+	StopAll()
+}
+
+type procManager struct {
+	mLog   *logging.MasterLogger
+	log    *logging.Logger
+	events *stream.Server // may be nil
+
+	mx    sync.Mutex
+	procs map[appcommon.ProcKey]*Proc
+}
+
+// NewProcManager creates a new ProcManager. events may be nil, in which case procs run without
+// publishing to the event-stream subsystem.
+func NewProcManager(mLog *logging.MasterLogger, events *stream.Server) ProcManager {
+	if mLog == nil {
+		mLog = logging.NewMasterLogger()
+	}
+
+	return &procManager{
+		mLog:   mLog,
+		log:    mLog.PackageLogger("proc_manager"),
+		events: events,
+		procs:  make(map[appcommon.ProcKey]*Proc),
+	}
+}
+
+func (pm *procManager) Start(conf appcommon.ProcConfig, disc appdisc.Updater) (appcommon.ProcKey, error) {
+	if conf.ProcKey.Null() {
+		conf.ProcKey = appcommon.RandProcKey()
+	}
+
+	pm.mx.Lock()
+	if _, ok := pm.procs[conf.ProcKey]; ok {
+		pm.mx.Unlock()
+		return appcommon.ProcKey{}, fmt.Errorf("proc of key %s is already running", conf.ProcKey)
+	}
+	// Reserve the key with a nil placeholder before releasing the lock, so a concurrent Start
+	// for the same key fails the check above instead of racing this call's construct-then-insert
+	// and silently clobbering whichever Proc loses the race.
+	pm.procs[conf.ProcKey] = nil
+	pm.mx.Unlock()
+
+	proc, err := NewProc(pm.mLog, conf, disc, pm.events)
+	if err != nil {
+		pm.mx.Lock()
+		delete(pm.procs, conf.ProcKey)
+		pm.mx.Unlock()
+		return appcommon.ProcKey{}, fmt.Errorf("construct proc: %w", err)
+	}
+	if err := proc.Start(); err != nil {
+		pm.mx.Lock()
+		delete(pm.procs, conf.ProcKey)
+		pm.mx.Unlock()
+		return appcommon.ProcKey{}, err
+	}
+
+	pm.mx.Lock()
+	pm.procs[conf.ProcKey] = proc
+	pm.mx.Unlock()
+
+	return conf.ProcKey, nil
+}
+
+func (pm *procManager) Stop(key appcommon.ProcKey) error {
+	pm.mx.Lock()
+	proc, ok := pm.procs[key]
+	if ok && proc != nil {
+		delete(pm.procs, key)
+	}
+	pm.mx.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no proc of key %s is running", key)
+	}
+	if proc == nil {
+		// Start for this key is still in its reserve-then-construct window: there is no *Proc
+		// yet to stop. Report it as not-yet-running rather than deleting the reservation out
+		// from under the in-flight Start and dereferencing a nil *Proc.
+		return fmt.Errorf("proc of key %s is still starting, try again", key)
+	}
+
+	return proc.Stop()
+}
+
+func (pm *procManager) Proc(key appcommon.ProcKey) (*Proc, bool) {
+	pm.mx.Lock()
+	defer pm.mx.Unlock()
+
+	proc, ok := pm.procs[key]
+	if proc == nil {
+		return nil, false
+	}
+	return proc, ok
+}
+
+func (pm *procManager) RestartCount(key appcommon.ProcKey) (int, bool) {
+	pm.mx.Lock()
+	defer pm.mx.Unlock()
+
+	proc, ok := pm.procs[key]
+	if !ok || proc == nil {
+		return 0, false
+	}
+	return proc.RestartCount(), true
+}
+
+func (pm *procManager) ProcByAppName(appName string) []appcommon.ProcKey {
+	pm.mx.Lock()
+	defer pm.mx.Unlock()
+
+	var keys []appcommon.ProcKey
+	for key, proc := range pm.procs {
+		if proc != nil && proc.conf.AppName == appName {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (pm *procManager) List() []appcommon.ProcKey {
+	pm.mx.Lock()
+	defer pm.mx.Unlock()
+
+	keys := make([]appcommon.ProcKey, 0, len(pm.procs))
+	for key, proc := range pm.procs {
+		if proc != nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (pm *procManager) StopAll() {
+	pm.mx.Lock()
+	procs := pm.procs
+	pm.procs = make(map[appcommon.ProcKey]*Proc)
+	pm.mx.Unlock()
+
+	for key, proc := range procs {
+		if proc == nil {
+			continue
+		}
+		if err := proc.Stop(); err != nil {
+			pm.log.WithError(err).WithField("proc_key", key).Warn("Failed to stop proc.")
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package appserver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+)
+
+func TestProcShouldRestart(t *testing.T) {
+	someErr := errors.New("boom")
+
+	cases := []struct {
+		policy  appcommon.RestartPolicy
+		runErr  error
+		restart bool
+	}{
+		{appcommon.RestartPolicyNever, someErr, false},
+		{appcommon.RestartPolicyNever, nil, false},
+		{appcommon.RestartPolicyOnFailure, someErr, true},
+		{appcommon.RestartPolicyOnFailure, nil, false},
+		{appcommon.RestartPolicyAlways, someErr, true},
+		{appcommon.RestartPolicyAlways, nil, true},
+	}
+
+	for _, c := range cases {
+		p := &Proc{conf: appcommon.ProcConfig{RestartPolicy: c.policy}}
+		assert.Equal(t, c.restart, p.shouldRestart(c.runErr), "policy=%s runErr=%v", c.policy, c.runErr)
+	}
+}
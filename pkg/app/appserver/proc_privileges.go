@@ -0,0 +1,7 @@
+package appserver
+
+import "errors"
+
+// errPrivilegesUnsupported is returned by applyProcPrivileges on platforms that cannot apply a
+// ProcConfig's declared Capabilities/AmbientCapabilities/RunAsUID/RunAsGID/NetNS.
+var errPrivilegesUnsupported = errors.New("declarative capabilities, uid/gid and network namespaces are only supported on linux")
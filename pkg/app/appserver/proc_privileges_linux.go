@@ -0,0 +1,152 @@
+// +build linux
+
+package appserver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+)
+
+// capsByName maps the well-known CAP_* names to their kernel capability numbers (see
+// include/uapi/linux/capability.h). Only the subset relevant to skywire apps (chiefly the VPN
+// client/server, which needs CAP_NET_ADMIN) is listed; NewProc fails loudly on unknown names
+// rather than silently ignoring them.
+var capsByName = map[string]uintptr{
+	"CAP_NET_ADMIN":        unix.CAP_NET_ADMIN,
+	"CAP_NET_RAW":          unix.CAP_NET_RAW,
+	"CAP_NET_BIND_SERVICE": unix.CAP_NET_BIND_SERVICE,
+	"CAP_SYS_ADMIN":        unix.CAP_SYS_ADMIN,
+	"CAP_SETUID":           unix.CAP_SETUID,
+	"CAP_SETGID":           unix.CAP_SETGID,
+}
+
+func capBits(names []string) ([]uintptr, error) {
+	bits := make([]uintptr, 0, len(names))
+	for _, name := range names {
+		bit, ok := capsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown capability %q", name)
+		}
+		bits = append(bits, bit)
+	}
+	return bits, nil
+}
+
+// visorEffectiveCaps returns the set of capability names currently held (effective) by this
+// (the visor) process, read from /proc/self/status.
+func visorEffectiveCaps() (map[string]bool, error) {
+	raw, err := ioutil.ReadFile("/proc/self/status")
+	if err != nil {
+		return nil, err
+	}
+
+	var effHex string
+	for _, line := range strings.Split(string(raw), "\n") {
+		if strings.HasPrefix(line, "CapEff:") {
+			effHex = strings.TrimSpace(strings.TrimPrefix(line, "CapEff:"))
+			break
+		}
+	}
+	if effHex == "" {
+		return nil, fmt.Errorf("CapEff not found in /proc/self/status")
+	}
+
+	eff, err := strconv.ParseUint(effHex, 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse CapEff: %w", err)
+	}
+
+	held := make(map[string]bool, len(capsByName))
+	for name, bit := range capsByName {
+		held[name] = eff&(uint64(1)<<uint(bit)) != 0
+	}
+	return held, nil
+}
+
+// applyProcPrivileges declares conf's Capabilities/AmbientCapabilities/RunAsUID/RunAsGID/NetNS on
+// cmd, so that only the procs that ask for elevated privilege get it - everything else keeps
+// running as the visor itself.
+//
+// Capabilities and AmbientCapabilities are both validated against the visor's held set, then
+// merged into a single ambient set: ambient capabilities are what let the child keep the declared
+// caps across the switch to RunAsUID/RunAsGID, since the Go runtime raises PR_SET_KEEPCAPS and
+// applies the ambient set in the forked child, before calling execve, whenever
+// SysProcAttr.AmbientCaps is non-empty. There is no non-ambient way to hand a capability to a
+// child that's also dropping privilege via RunAsUID/RunAsGID, so Capabilities on its own is
+// treated as shorthand for AmbientCapabilities rather than a separate mechanism.
+func applyProcPrivileges(cmd *exec.Cmd, conf appcommon.ProcConfig) error {
+	if len(conf.Capabilities) == 0 && len(conf.AmbientCapabilities) == 0 &&
+		conf.RunAsUID == nil && conf.RunAsGID == nil && conf.NetNS == "" {
+		return nil
+	}
+
+	held, err := visorEffectiveCaps()
+	if err != nil {
+		return fmt.Errorf("determine visor capabilities: %w", err)
+	}
+
+	declared := make([]string, 0, len(conf.Capabilities)+len(conf.AmbientCapabilities))
+	declared = append(declared, conf.Capabilities...)
+	declared = append(declared, conf.AmbientCapabilities...)
+	for _, name := range declared {
+		if !held[name] {
+			return fmt.Errorf("proc %s declares capability %s which the visor does not hold", conf.ProcKey, name)
+		}
+	}
+
+	// Capabilities alone would otherwise be validated against the visor's held set above and then
+	// silently dropped: declaring a capability only takes effect once it is in the ambient set the
+	// kernel actually carries across execve, so fold it in here rather than requiring every caller
+	// to duplicate it into AmbientCapabilities too.
+	ambientNames := append([]string{}, conf.AmbientCapabilities...)
+	for _, name := range conf.Capabilities {
+		already := false
+		for _, existing := range ambientNames {
+			if existing == name {
+				already = true
+				break
+			}
+		}
+		if !already {
+			ambientNames = append(ambientNames, name)
+		}
+	}
+
+	ambient, err := capBits(ambientNames)
+	if err != nil {
+		return err
+	}
+
+	attr := &syscall.SysProcAttr{AmbientCaps: ambient}
+	if conf.RunAsUID != nil || conf.RunAsGID != nil {
+		cred := &syscall.Credential{}
+		if conf.RunAsUID != nil {
+			cred.Uid = *conf.RunAsUID
+		}
+		if conf.RunAsGID != nil {
+			cred.Gid = *conf.RunAsGID
+		}
+		attr.Credential = cred
+	}
+	cmd.SysProcAttr = attr
+
+	if conf.NetNS != "" {
+		nsenterPath, err := exec.LookPath("nsenter")
+		if err != nil {
+			return fmt.Errorf("proc declares NetNS but nsenter is not available: %w", err)
+		}
+
+		cmd.Args = append([]string{"nsenter", "--net=" + conf.NetNS, "--", cmd.Path}, cmd.Args[1:]...)
+		cmd.Path = nsenterPath
+	}
+
+	return nil
+}
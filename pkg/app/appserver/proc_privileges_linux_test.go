@@ -0,0 +1,20 @@
+// +build linux
+
+package appserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestCapBits(t *testing.T) {
+	bits, err := capBits([]string{"CAP_NET_ADMIN", "CAP_SETUID"})
+	require.NoError(t, err)
+	assert.Equal(t, []uintptr{unix.CAP_NET_ADMIN, unix.CAP_SETUID}, bits)
+
+	_, err = capBits([]string{"CAP_BOGUS"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,20 @@
+// +build !linux
+
+package appserver
+
+import (
+	"os/exec"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+)
+
+// applyProcPrivileges is a no-op on non-Linux platforms. It errors if conf declares any of the
+// Linux-only privilege fields, rather than silently running the proc unprivileged or unconfined.
+func applyProcPrivileges(cmd *exec.Cmd, conf appcommon.ProcConfig) error {
+	if len(conf.Capabilities) == 0 && len(conf.AmbientCapabilities) == 0 &&
+		conf.RunAsUID == nil && conf.RunAsGID == nil && conf.NetNS == "" {
+		return nil
+	}
+
+	return errPrivilegesUnsupported
+}
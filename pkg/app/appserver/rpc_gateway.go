@@ -0,0 +1,55 @@
+package appserver
+
+import (
+	"github.com/skycoin/skycoin/src/util/logging"
+
+	"github.com/skycoin/skywire/pkg/app/appcommon"
+	"github.com/skycoin/skywire/pkg/app/appevent/stream"
+)
+
+// RPCIngressGateway is the RPC gateway through which a proc calls back into the visor
+// (e.g. to dial/listen on the skywire network).
+type RPCIngressGateway struct {
+	log *logging.Logger
+
+	cm *connManager
+	lm *listenerManager
+
+	events  *stream.Server // may be nil
+	procKey appcommon.ProcKey
+	appName string
+}
+
+// NewRPCGateway constructs a new RPCIngressGateway. events may be nil, in which case RPC calls
+// are not published to the event stream.
+//
+// This snapshot of the package only carries the conn/listener-count bookkeeping half of the
+// gateway (cm/lm); the actual exported RPC methods a proc calls (Dial, Listen, Write, Read, ...)
+// live elsewhere and aren't present here. publishRPCCall is kept as the hook those methods are
+// expected to call - see stream.EventRPCCall - but is not wired to anything in this tree.
+func NewRPCGateway(log *logging.Logger, events *stream.Server, procKey appcommon.ProcKey, appName string) *RPCIngressGateway {
+	return &RPCIngressGateway{
+		log:     log,
+		cm:      newConnManager(),
+		lm:      newListenerManager(),
+		events:  events,
+		procKey: procKey,
+		appName: appName,
+	}
+}
+
+// publishRPCCall reports an RPC method call on the event stream. It is a no-op if no event
+// stream server was supplied to NewRPCGateway. Exported RPC gateway methods should call this;
+// see the doc comment on NewRPCGateway for why none do in this tree.
+func (gw *RPCIngressGateway) publishRPCCall(method string) { // nolint:unused
+	if gw.events == nil {
+		return
+	}
+
+	gw.events.Publish(stream.ProcEvent{
+		Kind:    stream.EventRPCCall,
+		ProcKey: gw.procKey,
+		AppName: gw.appName,
+		Method:  method,
+	})
+}
@@ -0,0 +1,65 @@
+package servicedisc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/SkycoinProject/skycoin/src/util/logging"
+)
+
+// Client queries the skywire service discovery for advertised Services.
+type Client struct {
+	log   *logging.Logger
+	addr  string
+	httpC *http.Client
+}
+
+// NewClient constructs a Client that talks to the service discovery at addr (e.g.
+// "https://service-discovery.skywire.skycoin.com").
+func NewClient(log *logging.Logger, addr string) *Client {
+	if log == nil {
+		log = logging.MustGetLogger("servicedisc")
+	}
+
+	return &Client{
+		log:   log,
+		addr:  addr,
+		httpC: &http.Client{},
+	}
+}
+
+// Services returns the Services advertised to the discovery that satisfy f.
+func (c *Client) Services(ctx context.Context, f Filter) ([]Service, error) {
+	req, err := http.NewRequest(http.MethodGet, c.addr+"/api/services", nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.URL.RawQuery = f.Query().Encode()
+
+	resp, err := c.httpC.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("service discovery returned status %d", resp.StatusCode)
+	}
+
+	var services []Service
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		return nil, fmt.Errorf("decode service discovery response: %w", err)
+	}
+
+	out := services[:0]
+	for _, svc := range services {
+		if f.Match(svc) {
+			out = append(out, svc)
+		}
+	}
+
+	return out, nil
+}
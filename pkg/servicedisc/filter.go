@@ -0,0 +1,56 @@
+package servicedisc
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/SkycoinProject/dmsg/cipher"
+)
+
+// Filter narrows down which advertised Services a Client.Services call returns.
+type Filter struct {
+	Type         string
+	Country      string
+	MinBandwidth int
+	ExcludePKs   []cipher.PubKey
+}
+
+// Query encodes the Filter as URL query parameters for the service discovery's HTTP API.
+func (f Filter) Query() url.Values {
+	q := url.Values{}
+
+	q.Set("type", f.Type)
+
+	if f.Country != "" {
+		q.Set("country", f.Country)
+	}
+	if f.MinBandwidth > 0 {
+		q.Set("min_bandwidth", strconv.Itoa(f.MinBandwidth))
+	}
+	for _, pk := range f.ExcludePKs {
+		q.Add("exclude", pk.String())
+	}
+
+	return q
+}
+
+// Match reports whether svc passes the filter. Query sends every set field of the filter,
+// ExcludePKs included, to the discovery; Match re-checks every one of them client-side as a
+// safety net.
+func (f Filter) Match(svc Service) bool {
+	if f.Type != "" && svc.Type != f.Type {
+		return false
+	}
+	if f.Country != "" && svc.Country != f.Country {
+		return false
+	}
+	if f.MinBandwidth > 0 && svc.Bandwidth < f.MinBandwidth {
+		return false
+	}
+	for _, pk := range f.ExcludePKs {
+		if svc.Addr == pk {
+			return false
+		}
+	}
+	return true
+}
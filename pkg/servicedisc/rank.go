@@ -0,0 +1,17 @@
+package servicedisc
+
+import "sort"
+
+// RankByLastSeen sorts services by LastSeen, most-recently-seen first. It is used as the
+// fallback ranking when latency probing (which requires actually dialing each candidate) isn't
+// available or hasn't completed yet.
+func RankByLastSeen(services []Service) []Service {
+	ranked := make([]Service, len(services))
+	copy(ranked, services)
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].LastSeen.After(ranked[j].LastSeen)
+	})
+
+	return ranked
+}
@@ -0,0 +1,32 @@
+package servicedisc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankByLastSeen(t *testing.T) {
+	now := time.Now()
+
+	oldest := Service{Country: "oldest", LastSeen: now.Add(-2 * time.Hour)}
+	middle := Service{Country: "middle", LastSeen: now.Add(-1 * time.Hour)}
+	newest := Service{Country: "newest", LastSeen: now}
+
+	ranked := RankByLastSeen([]Service{oldest, newest, middle})
+
+	assert.Equal(t, []Service{newest, middle, oldest}, ranked)
+}
+
+func TestRankByLastSeenDoesNotMutateInput(t *testing.T) {
+	now := time.Now()
+	in := []Service{
+		{Country: "a", LastSeen: now.Add(-time.Hour)},
+		{Country: "b", LastSeen: now},
+	}
+
+	_ = RankByLastSeen(in)
+
+	assert.Equal(t, "a", in[0].Country, "RankByLastSeen must not reorder its input slice")
+}
@@ -0,0 +1,26 @@
+// Package servicedisc implements a client for the skywire service discovery, which lets apps
+// (e.g. the VPN client) find advertised servers of a given service type without being given a
+// specific public key up front.
+package servicedisc
+
+import (
+	"time"
+
+	"github.com/SkycoinProject/dmsg/cipher"
+)
+
+// Service types advertised via the service discovery.
+const (
+	ServiceTypeVPN         = "vpn"
+	ServiceTypePublicVisor = "visor"
+	ServiceTypeProxy       = "proxy"
+)
+
+// Service is a single advertised entry in the service discovery.
+type Service struct {
+	Addr      cipher.PubKey `json:"address"`
+	Type      string        `json:"type"`
+	Country   string        `json:"country,omitempty"`
+	Bandwidth int           `json:"bandwidth,omitempty"` // advertised bandwidth, in kbps
+	LastSeen  time.Time     `json:"last_seen"`
+}